@@ -0,0 +1,185 @@
+// Command rbxmesh inspects, validates, and converts Roblox mesh files from
+// the command line.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RobloxAPI/rbxmesh"
+)
+
+// errValidationFailed is returned by runValidate when the mesh has one or
+// more problems, after they have already been printed to stdout. main treats
+// it like any other error, exiting with status 1.
+var errValidationFailed = errors.New("validation failed")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "info":
+		err = runInfo(os.Args[2:])
+	case "convert":
+		err = runConvert(os.Args[2:])
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "rbxmesh: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rbxmesh:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+	rbxmesh info <file>
+	rbxmesh convert -in <file> -out <file> [-version <version>]
+	rbxmesh validate <file>`)
+}
+
+// readMesh loads a mesh from path, dispatching on its extension.
+func readMesh(path string) (*rbxmesh.Mesh, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := &rbxmesh.Mesh{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mesh":
+		if _, err := m.ReadFrom(f); err != nil {
+			return nil, err
+		}
+	case ".obj":
+		if err := m.ReadOBJ(f); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s: unrecognized input format", path)
+	}
+	return m, nil
+}
+
+// writeMesh saves m to path, dispatching on its extension. version is used
+// only when writing a .mesh file.
+func writeMesh(m *rbxmesh.Mesh, path string, version rbxmesh.Version) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mesh":
+		m.Version = version
+		_, err = m.WriteTo(f)
+	case ".obj":
+		err = m.WriteOBJ(f)
+	case ".gltf":
+		err = m.WriteGLTF(f, nil)
+	case ".glb":
+		err = m.WriteGLB(f, nil)
+	default:
+		return fmt.Errorf("%s: unrecognized output format", path)
+	}
+	return err
+}
+
+func runInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("info: expected a single file argument")
+	}
+
+	m, err := readMesh(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	min := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, v := range m.Vertices {
+		for i := 0; i < 3; i++ {
+			if v.Position[i] < min[i] {
+				min[i] = v.Position[i]
+			}
+			if v.Position[i] > max[i] {
+				max[i] = v.Position[i]
+			}
+		}
+	}
+	if len(m.Vertices) == 0 {
+		min, max = [3]float64{}, [3]float64{}
+	}
+
+	fmt.Printf("version:   %s\n", m.Version)
+	fmt.Printf("vertices:  %d\n", len(m.Vertices))
+	fmt.Printf("faces:     %d\n", len(m.Faces))
+	fmt.Printf("has color: %t\n", m.HasColor)
+	fmt.Printf("bounds:    [%g %g %g] - [%g %g %g]\n",
+		min[0], min[1], min[2], max[0], max[1], max[2])
+	return nil
+}
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	in := fs.String("in", "", "input mesh file (.mesh, .obj)")
+	out := fs.String("out", "", "output mesh file (.mesh, .obj, .gltf, .glb)")
+	versionFlag := fs.String("version", rbxmesh.Version2_00.String(), "version to use when writing a .mesh file")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("convert: -in and -out are required")
+	}
+	version := rbxmesh.VersionFromString(*versionFlag)
+	if version == rbxmesh.VersionUnknown {
+		return fmt.Errorf("convert: unrecognized -version %q", *versionFlag)
+	}
+
+	m, err := readMesh(*in)
+	if err != nil {
+		return err
+	}
+	return writeMesh(m, *out, version)
+}
+
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("validate: expected a single file argument")
+	}
+
+	m, err := readMesh(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	errs := m.Validate()
+	for _, e := range errs {
+		fmt.Println(e)
+	}
+	if len(errs) > 0 {
+		return errValidationFailed
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RobloxAPI/rbxmesh"
+)
+
+// captureStdout redirects os.Stdout for the duration of fn and returns what
+// was written, since runInfo and runValidate print directly to it.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func writeTempMesh(t *testing.T, name string, m *rbxmesh.Mesh) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := m.WriteOBJ(f); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func goodMesh() *rbxmesh.Mesh {
+	return &rbxmesh.Mesh{
+		Vertices: []rbxmesh.MeshVertex{
+			{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 1, 0}},
+			{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}},
+			{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}},
+		},
+		Faces: []rbxmesh.MeshFace{{0, 1, 2}},
+	}
+}
+
+func TestRunInfoSuccess(t *testing.T) {
+	path := writeTempMesh(t, "mesh.obj", goodMesh())
+	var err error
+	out := captureStdout(t, func() {
+		err = runInfo([]string{path})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains([]byte(out), []byte("vertices:  3")) {
+		t.Errorf("expected info output to report 3 vertices, got:\n%s", out)
+	}
+}
+
+func TestRunInfoMissingFile(t *testing.T) {
+	if err := runInfo([]string{filepath.Join(t.TempDir(), "missing.obj")}); err == nil {
+		t.Fatal("expected an error reading a nonexistent file")
+	}
+}
+
+func TestRunConvertSuccess(t *testing.T) {
+	in := writeTempMesh(t, "mesh.obj", goodMesh())
+	out := filepath.Join(t.TempDir(), "mesh.gltf")
+	if err := runConvert([]string{"-in", in, "-out", out}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("expected converted output file to exist: %v", err)
+	}
+}
+
+func TestRunConvertUnrecognizedFormat(t *testing.T) {
+	in := writeTempMesh(t, "mesh.obj", goodMesh())
+	out := filepath.Join(t.TempDir(), "mesh.unknown")
+	if err := runConvert([]string{"-in", in, "-out", out}); err == nil {
+		t.Fatal("expected an error converting to an unrecognized output format")
+	}
+}
+
+func TestRunValidateSuccess(t *testing.T) {
+	path := writeTempMesh(t, "mesh.obj", goodMesh())
+	if err := runValidate([]string{path}); err != nil {
+		t.Fatalf("expected a clean mesh to validate without error, got %v", err)
+	}
+}
+
+func TestRunValidateFindsProblems(t *testing.T) {
+	bad := &rbxmesh.Mesh{
+		Vertices: []rbxmesh.MeshVertex{
+			{Position: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}},
+		},
+		Faces: []rbxmesh.MeshFace{{0, 1, 1}}, // degenerate: repeated index
+	}
+	path := writeTempMesh(t, "bad.obj", bad)
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runValidate([]string{path})
+	})
+	if err != errValidationFailed {
+		t.Fatalf("expected errValidationFailed, got %v", err)
+	}
+	if len(out) == 0 {
+		t.Error("expected validation problems to be printed")
+	}
+}
@@ -0,0 +1,255 @@
+package rbxmesh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Header describes the layout of a mesh file, as read or to be written by a
+// Decoder or Encoder.
+type Header struct {
+	Version     Version
+	HasColor    bool
+	NumVertices int
+	NumFaces    int
+}
+
+// Decoder reads a mesh file one vertex or face at a time, rather than
+// allocating the full Vertices and Faces slices up front. This bounds memory
+// use when working with meshes that have hundreds of thousands of vertices.
+//
+// Only Version2_00 supports streaming decode: Version1_00 and Version1_01
+// store each face as three inline vertex blocks rather than indices into a
+// shared vertex pool, so their vertices cannot be produced independently of
+// their faces.
+type Decoder struct {
+	r      *bufio.Reader
+	header Header
+	nv, nf int // number of vertices/faces read so far
+	vsize  int // size in bytes of one encoded vertex
+}
+
+// NewDecoder reads the header of a mesh file from r and returns a Decoder
+// positioned to read the vertices and faces that follow.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	buf := bufio.NewReader(r)
+	line, _, err := buf.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	version := VersionFromString(string(line))
+	if version != Version2_00 {
+		return nil, errors.New("rbxmesh: streaming decode is only supported for version 2.00")
+	}
+	header, vsize, err := readV2Header(buf)
+	if err != nil {
+		return nil, err
+	}
+	header.Version = version
+	return &Decoder{r: buf, header: header, vsize: vsize}, nil
+}
+
+// readV2Header reads a Version2_00 header, excluding the leading version
+// line, from buf.
+func readV2Header(buf *bufio.Reader) (header Header, vsize int, err error) {
+	b := make([]byte, nHeader)
+	if _, err := io.ReadFull(buf, b[:nHeaderSize]); err != nil {
+		return header, 0, err
+	}
+	if int(binary.LittleEndian.Uint16(b)) != nHeader {
+		return header, 0, errors.New("unexpected header size")
+	}
+	if _, err := io.ReadFull(buf, b[nHeaderSize:nHeader]); err != nil {
+		return header, 0, err
+	}
+
+	switch int(b[2]) {
+	case nVertex:
+		header.HasColor = false
+		vsize = nVertex
+	case nColor:
+		header.HasColor = true
+		vsize = nColor
+	default:
+		return header, 0, errors.New("unexpected vertex size")
+	}
+	if int(b[3]) != nFace {
+		return header, 0, errors.New("unexpected face size")
+	}
+	header.NumVertices = int(binary.LittleEndian.Uint32(b[4:8]))
+	header.NumFaces = int(binary.LittleEndian.Uint32(b[8:12]))
+	return header, vsize, nil
+}
+
+// Version returns the version of the mesh file being decoded.
+func (d *Decoder) Version() Version { return d.header.Version }
+
+// HasColor reports whether each vertex in the mesh file carries color data.
+func (d *Decoder) HasColor() bool { return d.header.HasColor }
+
+// NumVertices returns the total number of vertices in the mesh file, as
+// declared by its header.
+func (d *Decoder) NumVertices() int { return d.header.NumVertices }
+
+// NumFaces returns the total number of faces in the mesh file, as declared
+// by its header.
+func (d *Decoder) NumFaces() int { return d.header.NumFaces }
+
+// skipVertices discards any vertices not yet read by NextVertex, so that
+// NextFace can be called without requiring the caller to drain every
+// vertex first.
+func (d *Decoder) skipVertices() error {
+	for ; d.nv < d.header.NumVertices; d.nv++ {
+		if _, err := io.CopyN(io.Discard, d.r, int64(d.vsize)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NextVertex reads and returns the next vertex in the mesh file. It returns
+// io.EOF once all NumVertices vertices have been read.
+func (d *Decoder) NextVertex() (MeshVertex, error) {
+	if d.nv >= d.header.NumVertices {
+		return MeshVertex{}, io.EOF
+	}
+	b := make([]byte, d.vsize)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return MeshVertex{}, err
+	}
+	d.nv++
+
+	vec := func(b []byte) [3]float64 {
+		return [3]float64{
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))),
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))),
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))),
+		}
+	}
+	v := MeshVertex{
+		Position: vec(b[0:12]),
+		Normal:   vec(b[12:24]),
+		Texture:  vec(b[24:36]),
+	}
+	if d.header.HasColor {
+		copy(v.Color[:], b[36:40])
+	}
+	return v, nil
+}
+
+// NextFace reads and returns the next face in the mesh file. It returns
+// io.EOF once all NumFaces faces have been read. If called before all
+// vertices have been read via NextVertex, the remaining vertices are
+// skipped automatically.
+func (d *Decoder) NextFace() (MeshFace, error) {
+	if d.nf == 0 {
+		if err := d.skipVertices(); err != nil {
+			return MeshFace{}, err
+		}
+	}
+	if d.nf >= d.header.NumFaces {
+		return MeshFace{}, io.EOF
+	}
+	var b [nFace]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		return MeshFace{}, err
+	}
+	d.nf++
+	return MeshFace{
+		int(binary.LittleEndian.Uint32(b[0:4])),
+		int(binary.LittleEndian.Uint32(b[4:8])),
+		int(binary.LittleEndian.Uint32(b[8:12])),
+	}, nil
+}
+
+// Encoder writes a mesh file one vertex or face at a time, as the symmetric
+// counterpart to Decoder. As with Decoder, only Version2_00 is supported.
+type Encoder struct {
+	w      *bufio.Writer
+	header Header
+	nv, nf int
+	vsize  int
+}
+
+// NewEncoder writes the header described by hdr to w and returns an Encoder
+// ready to accept hdr.NumVertices vertices followed by hdr.NumFaces faces.
+func NewEncoder(w io.Writer, hdr Header) (*Encoder, error) {
+	if hdr.Version != Version2_00 {
+		return nil, errors.New("rbxmesh: streaming encode is only supported for version 2.00")
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(hdr.Version.String() + "\n"); err != nil {
+		return nil, err
+	}
+
+	e := &Encoder{w: bw, header: hdr}
+	if hdr.HasColor {
+		e.vsize = nColor
+	} else {
+		e.vsize = nVertex
+	}
+
+	b := make([]byte, nHeader)
+	binary.LittleEndian.PutUint16(b[0:2], uint16(nHeader))
+	b[2] = byte(e.vsize)
+	b[3] = byte(nFace)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(hdr.NumVertices))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(hdr.NumFaces))
+	if _, err := bw.Write(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// WriteVertex writes the next vertex to the mesh file.
+func (e *Encoder) WriteVertex(v MeshVertex) error {
+	if e.nv >= e.header.NumVertices {
+		return errors.New("rbxmesh: too many vertices written")
+	}
+	b := make([]byte, e.vsize)
+	putvec := func(b []byte, v [3]float64) {
+		binary.LittleEndian.PutUint32(b[0:4], math.Float32bits(float32(v[0])))
+		binary.LittleEndian.PutUint32(b[4:8], math.Float32bits(float32(v[1])))
+		binary.LittleEndian.PutUint32(b[8:12], math.Float32bits(float32(v[2])))
+	}
+	putvec(b[0:12], v.Position)
+	putvec(b[12:24], v.Normal)
+	putvec(b[24:36], v.Texture)
+	if e.header.HasColor {
+		copy(b[36:40], v.Color[:])
+	}
+	if _, err := e.w.Write(b); err != nil {
+		return err
+	}
+	e.nv++
+	return nil
+}
+
+// WriteFace writes the next face to the mesh file.
+func (e *Encoder) WriteFace(f MeshFace) error {
+	if e.nv < e.header.NumVertices {
+		return errors.New("rbxmesh: not all vertices written before first face")
+	}
+	if e.nf >= e.header.NumFaces {
+		return errors.New("rbxmesh: too many faces written")
+	}
+	var b [nFace]byte
+	binary.LittleEndian.PutUint32(b[0:4], uint32(f[0]))
+	binary.LittleEndian.PutUint32(b[4:8], uint32(f[1]))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(f[2]))
+	if _, err := e.w.Write(b[:]); err != nil {
+		return err
+	}
+	e.nf++
+	return nil
+}
+
+// Close flushes any buffered data to the underlying writer. It does not
+// close the writer itself.
+func (e *Encoder) Close() error {
+	return e.w.Flush()
+}
@@ -0,0 +1,122 @@
+package rbxmesh
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	vertices := []MeshVertex{
+		{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 0, 0}},
+		{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{1, 0, 0}},
+		{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 1, 0}},
+	}
+	faces := []MeshFace{{0, 1, 2}}
+
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, Header{
+		Version:     Version2_00,
+		NumVertices: len(vertices),
+		NumFaces:    len(faces),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vertices {
+		if err := enc.WriteVertex(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range faces {
+		if err := enc.WriteFace(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := NewDecoder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.NumVertices() != len(vertices) || dec.NumFaces() != len(faces) {
+		t.Fatalf("expected header counts %d/%d, got %d/%d", len(vertices), len(faces), dec.NumVertices(), dec.NumFaces())
+	}
+	for i, want := range vertices {
+		got, err := dec.NextVertex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Position != want.Position {
+			t.Errorf("vertex %d: expected position %v, got %v", i, want.Position, got.Position)
+		}
+	}
+	if _, err := dec.NextVertex(); err != io.EOF {
+		t.Errorf("expected io.EOF after reading all vertices, got %v", err)
+	}
+	for i, want := range faces {
+		got, err := dec.NextFace()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("face %d: expected %v, got %v", i, want, got)
+		}
+	}
+	if _, err := dec.NextFace(); err != io.EOF {
+		t.Errorf("expected io.EOF after reading all faces, got %v", err)
+	}
+}
+
+func TestEncoderWriteFaceBeforeVerticesFails(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, Header{Version: Version2_00, NumVertices: 1, NumFaces: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.WriteFace(MeshFace{0, 0, 0}); err == nil {
+		t.Error("expected an error writing a face before all vertices were written")
+	}
+}
+
+func TestDecoderSkipsUnreadVertices(t *testing.T) {
+	vertices := []MeshVertex{
+		{Position: [3]float64{0, 0, 0}},
+		{Position: [3]float64{1, 0, 0}},
+	}
+	faces := []MeshFace{{0, 1, 0}}
+
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, Header{Version: Version2_00, NumVertices: len(vertices), NumFaces: len(faces)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vertices {
+		if err := enc.WriteVertex(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range faces {
+		if err := enc.WriteFace(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := NewDecoder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Skip straight to NextFace without reading any vertices.
+	got, err := dec.NextFace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != faces[0] {
+		t.Errorf("expected %v, got %v", faces[0], got)
+	}
+}
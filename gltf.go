@@ -0,0 +1,303 @@
+package rbxmesh
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+)
+
+// glTF component type and target constants, as defined by the glTF 2.0
+// specification.
+const (
+	gltfComponentFloat        = 5126
+	gltfComponentUnsignedByte = 5121
+	gltfComponentUnsignedInt  = 5125
+
+	gltfTargetArrayBuffer        = 34962
+	gltfTargetElementArrayBuffer = 34963
+)
+
+const gltfGenerator = "rbxmesh"
+
+// GLTFOptions holds parameters for Mesh.WriteGLTF and Mesh.WriteGLB. A nil
+// *GLTFOptions is equivalent to the zero value.
+type GLTFOptions struct {
+	// Generator overrides the string written to asset.generator. If empty,
+	// a default value is used.
+	Generator string
+}
+
+func (o *GLTFOptions) generator() string {
+	if o == nil || o.Generator == "" {
+		return gltfGenerator
+	}
+	return o.Generator
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfNode struct {
+	Mesh int `json:"mesh"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+// pad4 returns b extended with zero bytes until its length is a multiple of
+// 4, as required for glTF bufferView alignment.
+func pad4(b []byte) []byte {
+	if n := len(b) % 4; n != 0 {
+		b = append(b, make([]byte, 4-n)...)
+	}
+	return b
+}
+
+// buildGLTF assembles a glTF document and its binary buffer from m. The
+// buffer's byteLength is included in the document's Buffers entry, but the
+// URI is left empty; WriteGLTF and WriteGLB fill it in appropriately for
+// their respective container formats.
+func buildGLTF(m *Mesh, opts *GLTFOptions) (doc gltfDocument, bin []byte, err error) {
+	nv := len(m.Vertices)
+	if nv == 0 {
+		return doc, nil, errors.New("rbxmesh: cannot export a mesh with no vertices to glTF")
+	}
+	var buf bytes.Buffer
+
+	putFloat := func(f float64) {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(float32(f)))
+		buf.Write(b[:])
+	}
+
+	// POSITION
+	posOffset := buf.Len()
+	min := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, v := range m.Vertices {
+		for i := 0; i < 3; i++ {
+			putFloat(v.Position[i])
+			if v.Position[i] < min[i] {
+				min[i] = v.Position[i]
+			}
+			if v.Position[i] > max[i] {
+				max[i] = v.Position[i]
+			}
+		}
+	}
+	posLength := buf.Len() - posOffset
+
+	// NORMAL
+	normOffset := buf.Len()
+	for _, v := range m.Vertices {
+		putFloat(v.Normal[0])
+		putFloat(v.Normal[1])
+		putFloat(v.Normal[2])
+	}
+	normLength := buf.Len() - normOffset
+
+	// TEXCOORD_0
+	uvOffset := buf.Len()
+	for _, v := range m.Vertices {
+		putFloat(v.Texture[0])
+		putFloat(1 - v.Texture[1])
+	}
+	uvLength := buf.Len() - uvOffset
+
+	// COLOR_0
+	var colorOffset, colorLength int
+	if m.HasColor {
+		colorOffset = buf.Len()
+		for _, v := range m.Vertices {
+			buf.Write(v.Color[:])
+		}
+		colorLength = buf.Len() - colorOffset
+	}
+
+	// indices
+	idxOffset := buf.Len()
+	for _, face := range m.Faces {
+		for _, index := range face {
+			if index < 0 || index >= nv {
+				return doc, nil, errors.New("index out of range")
+			}
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], uint32(index))
+			buf.Write(b[:])
+		}
+	}
+	idxLength := buf.Len() - idxOffset
+
+	bin = pad4(buf.Bytes())
+
+	doc.Asset = gltfAsset{Version: "2.0", Generator: opts.generator()}
+	doc.Scene = 0
+	doc.Scenes = []gltfScene{{Nodes: []int{0}}}
+	doc.Nodes = []gltfNode{{Mesh: 0}}
+	doc.Meshes = []gltfMesh{{Primitives: []gltfPrimitive{{
+		Attributes: map[string]int{},
+		Indices:    0,
+	}}}}
+	doc.Buffers = []gltfBuffer{{ByteLength: len(bin)}}
+
+	addView := func(offset, length, target int) int {
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{
+			Buffer:     0,
+			ByteOffset: offset,
+			ByteLength: length,
+			Target:     target,
+		})
+		return len(doc.BufferViews) - 1
+	}
+	addAccessor := func(view, componentType, count int, typ string, min, max []float64) int {
+		doc.Accessors = append(doc.Accessors, gltfAccessor{
+			BufferView:    view,
+			ComponentType: componentType,
+			Count:         count,
+			Type:          typ,
+			Min:           min,
+			Max:           max,
+		})
+		return len(doc.Accessors) - 1
+	}
+
+	posView := addView(posOffset, posLength, gltfTargetArrayBuffer)
+	doc.Meshes[0].Primitives[0].Attributes["POSITION"] = addAccessor(
+		posView, gltfComponentFloat, nv, "VEC3", min[:], max[:])
+
+	normView := addView(normOffset, normLength, gltfTargetArrayBuffer)
+	doc.Meshes[0].Primitives[0].Attributes["NORMAL"] = addAccessor(
+		normView, gltfComponentFloat, nv, "VEC3", nil, nil)
+
+	uvView := addView(uvOffset, uvLength, gltfTargetArrayBuffer)
+	doc.Meshes[0].Primitives[0].Attributes["TEXCOORD_0"] = addAccessor(
+		uvView, gltfComponentFloat, nv, "VEC2", nil, nil)
+
+	if m.HasColor {
+		colorView := addView(colorOffset, colorLength, gltfTargetArrayBuffer)
+		doc.Meshes[0].Primitives[0].Attributes["COLOR_0"] = addAccessor(
+			colorView, gltfComponentUnsignedByte, nv, "VEC4", nil, nil)
+		doc.Accessors[len(doc.Accessors)-1].Normalized = true
+	}
+
+	idxView := addView(idxOffset, idxLength, gltfTargetElementArrayBuffer)
+	doc.Meshes[0].Primitives[0].Indices = addAccessor(
+		idxView, gltfComponentUnsignedInt, len(m.Faces)*3, "SCALAR", nil, nil)
+
+	return doc, bin, nil
+}
+
+// WriteGLTF encodes the mesh as a glTF 2.0 JSON document and writes it to w.
+// The vertex and index data is embedded directly in the document as a
+// base64-encoded data URI; no external .bin file is produced. Vertex
+// positions and normals are written as VEC3 float accessors, texture
+// coordinates as a VEC2 float accessor with V flipped to glTF convention
+// (and W dropped), and, if m.HasColor is set, vertex colors as a VEC4
+// unsigned byte accessor. Indices are written as UNSIGNED_INT, since Roblox
+// meshes routinely exceed the 65,535 vertices addressable by
+// UNSIGNED_SHORT. opts may be nil to use the defaults.
+func (m *Mesh) WriteGLTF(w io.Writer, opts *GLTFOptions) error {
+	doc, bin, err := buildGLTF(m, opts)
+	if err != nil {
+		return err
+	}
+	doc.Buffers[0].URI = "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(bin)
+	return json.NewEncoder(w).Encode(&doc)
+}
+
+// WriteGLB encodes the mesh as a single binary glTF (.glb) container and
+// writes it to w: a 12-byte header, followed by a JSON chunk holding the
+// document built by WriteGLTF (minus the buffer's data URI) and a BIN chunk
+// holding the vertex and index data. Both chunks are padded to a 4-byte
+// boundary as required by the glTF 2.0 specification. opts may be nil to use
+// the defaults.
+func (m *Mesh) WriteGLB(w io.Writer, opts *GLTFOptions) error {
+	doc, bin, err := buildGLTF(m, opts)
+	if err != nil {
+		return err
+	}
+	js, err := json.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+
+	total := 12 + 8 + len(js) + 8 + len(bin)
+
+	var hdr [12]byte
+	copy(hdr[0:4], "glTF")
+	binary.LittleEndian.PutUint32(hdr[4:8], 2)
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(total))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+
+	var chunkHdr [8]byte
+	binary.LittleEndian.PutUint32(chunkHdr[0:4], uint32(len(js)))
+	binary.LittleEndian.PutUint32(chunkHdr[4:8], 0x4E4F534A) // "JSON"
+	if _, err := w.Write(chunkHdr[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(js); err != nil {
+		return err
+	}
+
+	binary.LittleEndian.PutUint32(chunkHdr[0:4], uint32(len(bin)))
+	binary.LittleEndian.PutUint32(chunkHdr[4:8], 0x004E4942) // "BIN\0"
+	if _, err := w.Write(chunkHdr[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(bin)
+	return err
+}
@@ -0,0 +1,81 @@
+package rbxmesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+func triangleMesh() *Mesh {
+	return &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{1, 0, 0}},
+			{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 1, 0}},
+		},
+		Faces: []MeshFace{{0, 1, 2}},
+	}
+}
+
+func TestWriteGLTFStructure(t *testing.T) {
+	m := triangleMesh()
+	var buf bytes.Buffer
+	if err := m.WriteGLTF(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc gltfDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON document: %v", err)
+	}
+	if len(doc.Meshes) != 1 || len(doc.Meshes[0].Primitives) != 1 {
+		t.Fatal("expected exactly one mesh with one primitive")
+	}
+	attrs := doc.Meshes[0].Primitives[0].Attributes
+	for _, name := range []string{"POSITION", "NORMAL", "TEXCOORD_0"} {
+		if _, ok := attrs[name]; !ok {
+			t.Errorf("expected %s attribute", name)
+		}
+	}
+	posAccessor := doc.Accessors[attrs["POSITION"]]
+	if posAccessor.Count != len(m.Vertices) {
+		t.Errorf("expected POSITION count %d, got %d", len(m.Vertices), posAccessor.Count)
+	}
+	if posAccessor.Min == nil || posAccessor.Max == nil {
+		t.Error("expected POSITION accessor to have min/max bounds")
+	}
+	idxAccessor := doc.Accessors[doc.Meshes[0].Primitives[0].Indices]
+	if idxAccessor.Count != len(m.Faces)*3 {
+		t.Errorf("expected %d indices, got %d", len(m.Faces)*3, idxAccessor.Count)
+	}
+}
+
+func TestWriteGLBHeader(t *testing.T) {
+	m := triangleMesh()
+	var buf bytes.Buffer
+	if err := m.WriteGLB(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	if len(b) < 12 {
+		t.Fatalf("expected at least a 12-byte header, got %d bytes", len(b))
+	}
+	if string(b[0:4]) != "glTF" {
+		t.Errorf("expected glTF magic, got %q", b[0:4])
+	}
+	if v := binary.LittleEndian.Uint32(b[4:8]); v != 2 {
+		t.Errorf("expected version 2, got %d", v)
+	}
+	if total := binary.LittleEndian.Uint32(b[8:12]); int(total) != len(b) {
+		t.Errorf("header total length %d does not match actual length %d", total, len(b))
+	}
+}
+
+func TestWriteGLTFEmptyMesh(t *testing.T) {
+	m := &Mesh{}
+	var buf bytes.Buffer
+	if err := m.WriteGLTF(&buf, nil); err == nil {
+		t.Fatal("expected an error exporting a mesh with no vertices, got nil")
+	}
+}
@@ -3,11 +3,9 @@ package rbxmesh
 
 import (
 	"bufio"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"strconv"
 )
 
@@ -35,6 +33,11 @@ const (
 	Version2_00    Version = 0 // Default
 	Version1_00    Version = 1
 	Version1_01    Version = 2
+	Version3_00    Version = 3
+	Version3_01    Version = 4
+	Version4_00    Version = 5
+	Version4_01    Version = 6
+	Version5_00    Version = 7
 )
 
 // String returns a string representation of the version. It matches the
@@ -48,6 +51,16 @@ func (v Version) String() string {
 		return "version 1.01"
 	case Version2_00:
 		return "version 2.00"
+	case Version3_00:
+		return "version 3.00"
+	case Version3_01:
+		return "version 3.01"
+	case Version4_00:
+		return "version 4.00"
+	case Version4_01:
+		return "version 4.01"
+	case Version5_00:
+		return "version 5.00"
 	default:
 		return "version x.xx"
 	}
@@ -65,6 +78,16 @@ func VersionFromString(s string) Version {
 		return Version1_01
 	case Version2_00.String():
 		return Version2_00
+	case Version3_00.String():
+		return Version3_00
+	case Version3_01.String():
+		return Version3_01
+	case Version4_00.String():
+		return Version4_00
+	case Version4_01.String():
+		return Version4_01
+	case Version5_00.String():
+		return Version5_00
 	}
 	return VersionUnknown
 }
@@ -89,6 +112,53 @@ type Mesh struct {
 	HasColor bool    // HasColor indicates whether each MeshVertex has color data.
 	Vertices []MeshVertex
 	Faces    []MeshFace
+
+	// LODs holds the mesh's level-of-detail ranges. Only used by
+	// Version3_00 and later.
+	LODs []LODRange
+	// Bones holds the mesh's skinning skeleton. Only used by Version4_00
+	// and later.
+	Bones []Bone
+	// Envelopes holds one bone envelope per entry in Vertices, giving the
+	// bone influences used to skin that vertex. Only used by Version4_00
+	// and later.
+	Envelopes []VertexEnvelope
+	// FaceSubsets partitions Faces into material subsets. Only used by
+	// Version5_00 and later.
+	FaceSubsets []FaceSubset
+	// NumHighQualityLODs is the number of entries at the start of LODs
+	// that are high-quality (as opposed to generated/simplified) LOD
+	// levels. Only used by Version5_00 and later.
+	NumHighQualityLODs int
+}
+
+// LODRange marks the start of a level-of-detail range within Mesh.Faces.
+// The first LODRange conventionally has a FaceIndex of 0.
+type LODRange struct {
+	FaceIndex int
+}
+
+// Bone is a single node in a mesh's skinning skeleton.
+type Bone struct {
+	Name        string
+	ParentIndex int16       // Index into Mesh.Bones, or -1 for a root bone.
+	Transform   [12]float32 // 3x4 row-major transform relative to the parent bone.
+}
+
+// VertexEnvelope gives the bone influences used to skin a single vertex. It
+// corresponds by index to an entry in Mesh.Vertices.
+type VertexEnvelope struct {
+	Bones   [4]byte // Indices into Mesh.Bones.
+	Weights [4]byte // Influence of each bone, out of 255.
+}
+
+// FaceSubset describes a contiguous range of Mesh.Faces (and the vertices
+// they reference) that share a material.
+type FaceSubset struct {
+	FacesBegin int
+	FacesCount int
+	VertsBegin int
+	VertsCount int
 }
 
 const nHeaderSize = 2
@@ -108,6 +178,11 @@ func (m *Mesh) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 	version := VersionFromString(string(line))
 	m.Version = version
+	m.LODs = nil
+	m.Bones = nil
+	m.Envelopes = nil
+	m.FaceSubsets = nil
+	m.NumHighQualityLODs = 0
 	switch version {
 	case Version1_00, Version1_01:
 		m.HasColor = false
@@ -155,78 +230,38 @@ func (m *Mesh) ReadFrom(r io.Reader) (n int64, err error) {
 		return rr.BytesRead(), nil
 
 	case Version2_00:
-		b := make([]byte, nColor)
-
-		// Header size
-		b = b[:nHeaderSize]
-		if _, err := buf.Read(b); err != nil {
+		header, vsize, err := readV2Header(buf)
+		if err != nil {
 			return rr.BytesRead(), err
 		}
-		switch int(binary.LittleEndian.Uint16(b)) {
-		case nHeader:
-			b = b[:nHeader]
-			if _, err := buf.Read(b[nHeaderSize : nHeader-nHeaderSize]); err != nil {
-				return rr.BytesRead(), err
-			}
-			switch int(b[2]) {
-			case nVertex:
-				m.HasColor = false
-			case nColor:
-				m.HasColor = true
-			default:
-				return rr.BytesRead(), errors.New("unexpected vertex size")
-			}
-			switch int(b[3]) {
-			case nFace:
-			default:
-				return rr.BytesRead(), errors.New("unexpected face size")
-			}
-			m.Vertices = make([]MeshVertex, int(binary.LittleEndian.Uint32(b[4:8])))
-			m.Faces = make([]MeshFace, int(binary.LittleEndian.Uint32(b[8:12])))
-
-		default:
-			return rr.BytesRead(), errors.New("unexpected header size")
-		}
+		header.Version = version
+		m.HasColor = header.HasColor
 
-		// Vertices
-		if m.HasColor {
-			b = b[:nColor]
-		} else {
-			b = b[:nVertex]
-		}
-		vec := func(b []byte) [3]float64 {
-			return [3]float64{
-				float64(math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))),
-				float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))),
-				float64(math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))),
-			}
-		}
-		for i, v := range m.Vertices {
-			if _, err := buf.Read(b); err != nil {
+		d := &Decoder{r: buf, header: header, vsize: vsize}
+		m.Vertices = make([]MeshVertex, header.NumVertices)
+		for i := range m.Vertices {
+			v, err := d.NextVertex()
+			if err != nil {
 				return rr.BytesRead(), err
 			}
-			v.Position = vec(b[0:12])
-			v.Normal = vec(b[12:24])
-			v.Texture = vec(b[24:36])
-			if m.HasColor {
-				copy(v.Color[:], b[36:40])
-			}
 			m.Vertices[i] = v
 		}
-
-		// Faces
-		b = b[:nFace]
-		for i, f := range m.Faces {
-			if _, err := buf.Read(b); err != nil {
+		m.Faces = make([]MeshFace, header.NumFaces)
+		for i := range m.Faces {
+			f, err := d.NextFace()
+			if err != nil {
 				return rr.BytesRead(), err
 			}
-			f[0] = int(binary.LittleEndian.Uint32(b[0:4]))
-			f[1] = int(binary.LittleEndian.Uint32(b[4:8]))
-			f[2] = int(binary.LittleEndian.Uint32(b[8:12]))
 			m.Faces[i] = f
 		}
 
 		return rr.BytesRead(), nil
+
+	case Version3_00, Version3_01, Version4_00, Version4_01, Version5_00:
+		if err := m.readV3Plus(buf, version); err != nil {
+			return rr.BytesRead(), err
+		}
+		return rr.BytesRead(), nil
 	}
 	return rr.BytesRead(), errors.New("unknown version")
 }
@@ -235,17 +270,16 @@ func (m *Mesh) ReadFrom(r io.Reader) (n int64, err error) {
 // representation, which is written to an io.Writer. The format is determined
 // by Mesh.Version.
 func (m *Mesh) WriteTo(w io.Writer) (n int64, err error) {
-	nn, err := w.Write([]byte(m.Version.String() + "\n"))
-	if n += int64(nn); err != nil {
-		return n, err
-	}
-
 	switch m.Version {
 	case Version1_00, Version1_01:
+		nn, err := w.Write([]byte(m.Version.String() + "\n"))
+		if n += int64(nn); err != nil {
+			return n, err
+		}
 		b := make([]byte, 0, 32)
 		b = strconv.AppendUint(b, uint64(len(m.Faces)), 32)
 		b = append(b, '\n')
-		nn, err := w.Write(b)
+		nn, err = w.Write(b)
 		if n += int64(nn); err != nil {
 			return n, err
 		}
@@ -275,63 +309,53 @@ func (m *Mesh) WriteTo(w io.Writer) (n int64, err error) {
 		return n, nil
 
 	case Version2_00:
-		b := make([]byte, 0, nColor)
-		put16 := binary.LittleEndian.PutUint16
-		put32 := binary.LittleEndian.PutUint32
-
-		// Header
-		b = b[:nHeader]
-		put16(b[0:2], uint16(nHeader))
-		if m.HasColor {
-			put16(b[2:4], uint16(nColor))
-		} else {
-			put16(b[2:4], uint16(nVertex))
-		}
-		put16(b[4:6], uint16(nFace))
-		put32(b[6:10], uint32(len(m.Vertices)))
-		put32(b[10:14], uint32(len(m.Faces)))
-		nn, err := w.Write(b)
-		if n += int64(nn); err != nil {
-			return n, err
-		}
-
-		// Vertices
-		putvec := func(b []byte, v [3]float64) {
-			binary.LittleEndian.PutUint32(b[0:4], math.Float32bits(float32(v[0])))
-			binary.LittleEndian.PutUint32(b[4:8], math.Float32bits(float32(v[1])))
-			binary.LittleEndian.PutUint32(b[8:12], math.Float32bits(float32(v[2])))
-		}
-		if m.HasColor {
-			b = b[:nColor]
-		} else {
-			b = b[:nVertex]
+		cw := &countingWriter{w: w}
+		enc, err := NewEncoder(cw, Header{
+			Version:     m.Version,
+			HasColor:    m.HasColor,
+			NumVertices: len(m.Vertices),
+			NumFaces:    len(m.Faces),
+		})
+		if err != nil {
+			return cw.n, err
 		}
 		for _, vertex := range m.Vertices {
-			putvec(b[0:12], vertex.Position)
-			putvec(b[12:24], vertex.Normal)
-			putvec(b[24:36], vertex.Texture)
-			if m.HasColor {
-				copy(b[36:40], vertex.Color[:])
-			}
-			nn, err = w.Write(b)
-			if n += int64(nn); err != nil {
-				return n, err
+			if err := enc.WriteVertex(vertex); err != nil {
+				return cw.n, err
 			}
 		}
-
-		// Faces
-		b = b[:nFace]
 		for _, face := range m.Faces {
-			put32(b[0:4], uint32(face[0]))
-			put32(b[4:8], uint32(face[1]))
-			put32(b[8:12], uint32(face[2]))
-			nn, err = w.Write(b)
-			if n += int64(nn); err != nil {
-				return n, err
+			if err := enc.WriteFace(face); err != nil {
+				return cw.n, err
 			}
 		}
+		if err := enc.Close(); err != nil {
+			return cw.n, err
+		}
+		return cw.n, nil
 
-		return n, nil
+	case Version3_00, Version3_01, Version4_00, Version4_01, Version5_00:
+		cw := &countingWriter{w: w}
+		if _, err := cw.Write([]byte(m.Version.String() + "\n")); err != nil {
+			return cw.n, err
+		}
+		if err := m.writeV3Plus(cw); err != nil {
+			return cw.n, err
+		}
+		return cw.n, nil
 	}
 	return 0, errors.New("unknown version")
 }
+
+// countingWriter wraps an io.Writer, tracking the number of bytes written
+// so far so that it can be reported as the n result of WriteTo.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	nn, err := c.w.Write(p)
+	c.n += int64(nn)
+	return nn, err
+}
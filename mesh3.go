@@ -0,0 +1,378 @@
+package rbxmesh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Versions 3.00 and later replace the fixed 12-byte Version2_00 header with
+// a size-prefixed one: the header itself grew as later versions added LOD
+// ranges, skinning, and face subsets, so the leading uint16 is read first
+// and the whole header of that size is consumed. Fields the version defines
+// are parsed and any bytes beyond them are skipped, which keeps ReadFrom
+// forward-compatible with larger header shapes it doesn't yet know about,
+// the same way Version2_00 already distinguishes a colored vertex from an
+// uncolored one by size rather than by a dedicated flag.
+const (
+	nHeaderV3 = 2 /*headerSize*/ + 2 /*vertexSize*/ + 2 /*faceSize*/ + 4 /*numVerts*/ + 4 /*numFaces*/ + 4 /*numLODs*/
+	nHeaderV4 = nHeaderV3 + 2 /*numBones*/ + 4                                                             /*nameTableBytes*/
+	nHeaderV5 = nHeaderV4 + 2 /*numSubsets*/ + 2                                                           /*numHQLODs*/
+
+	nLODEntry = 4 // uint32 face index
+
+	nBoneEntry = 4 /*nameIndex*/ + 2 /*pad*/ + 2 /*parentIndex*/ + 12*4 /*Transform*/
+
+	nEnvelope = 4 /*Bones*/ + 4 /*Weights*/
+
+	nFaceSubset = 4 + 4 + 4 + 4 // FacesBegin, FacesCount, VertsBegin, VertsCount
+)
+
+// hasSkinning reports whether version includes per-vertex bone envelopes
+// and a bone hierarchy.
+func hasSkinning(version Version) bool {
+	return version == Version4_00 || version == Version4_01 || version == Version5_00
+}
+
+// hasFaceSubsets reports whether version includes material face subsets.
+func hasFaceSubsets(version Version) bool {
+	return version == Version5_00
+}
+
+// readV3Plus decodes the Version3_00-and-later mesh formats: a size-prefixed
+// header, vertices, faces, LOD ranges, and (starting at Version4_00) a bone
+// hierarchy and per-vertex envelopes, and (starting at Version5_00) face
+// subsets.
+func (m *Mesh) readV3Plus(buf *bufio.Reader, version Version) error {
+	var hb [2]byte
+	if _, err := io.ReadFull(buf, hb[:]); err != nil {
+		return err
+	}
+	headerSize := int(binary.LittleEndian.Uint16(hb[:]))
+
+	var wantSize int
+	switch {
+	case hasFaceSubsets(version):
+		wantSize = nHeaderV5
+	case hasSkinning(version):
+		wantSize = nHeaderV4
+	default:
+		wantSize = nHeaderV3
+	}
+	if headerSize < wantSize {
+		return errors.New("unexpected header size")
+	}
+
+	h := make([]byte, headerSize-2)
+	if _, err := io.ReadFull(buf, h); err != nil {
+		return err
+	}
+
+	vertexSize := int(binary.LittleEndian.Uint16(h[0:2]))
+	faceSize := int(binary.LittleEndian.Uint16(h[2:4]))
+	numVerts := int(binary.LittleEndian.Uint32(h[4:8]))
+	numFaces := int(binary.LittleEndian.Uint32(h[8:12]))
+	numLODs := int(binary.LittleEndian.Uint32(h[12:16]))
+
+	m.HasColor = false
+	switch vertexSize {
+	case nVertex:
+		m.HasColor = false
+	case nColor:
+		m.HasColor = true
+	default:
+		return errors.New("unexpected vertex size")
+	}
+	if faceSize != nFace {
+		return errors.New("unexpected face size")
+	}
+
+	var numBones, nameTableBytes int
+	if hasSkinning(version) {
+		numBones = int(binary.LittleEndian.Uint16(h[16:18]))
+		nameTableBytes = int(binary.LittleEndian.Uint32(h[18:22]))
+	}
+
+	var numSubsets int
+	if hasFaceSubsets(version) {
+		numSubsets = int(binary.LittleEndian.Uint16(h[22:24]))
+		m.NumHighQualityLODs = int(binary.LittleEndian.Uint16(h[24:26]))
+	} else {
+		m.NumHighQualityLODs = 0
+	}
+
+	// Vertices
+	vb := make([]byte, nVertex)
+	if m.HasColor {
+		vb = make([]byte, nColor)
+	}
+	vec := func(b []byte) [3]float64 {
+		return [3]float64{
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))),
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))),
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))),
+		}
+	}
+	m.Vertices = make([]MeshVertex, numVerts)
+	for i := range m.Vertices {
+		if _, err := io.ReadFull(buf, vb); err != nil {
+			return err
+		}
+		v := MeshVertex{
+			Position: vec(vb[0:12]),
+			Normal:   vec(vb[12:24]),
+			Texture:  vec(vb[24:36]),
+		}
+		if m.HasColor {
+			copy(v.Color[:], vb[36:40])
+		}
+		m.Vertices[i] = v
+	}
+
+	// Faces
+	var fb [nFace]byte
+	m.Faces = make([]MeshFace, numFaces)
+	for i := range m.Faces {
+		if _, err := io.ReadFull(buf, fb[:]); err != nil {
+			return err
+		}
+		m.Faces[i] = MeshFace{
+			int(binary.LittleEndian.Uint32(fb[0:4])),
+			int(binary.LittleEndian.Uint32(fb[4:8])),
+			int(binary.LittleEndian.Uint32(fb[8:12])),
+		}
+	}
+
+	// LOD ranges
+	var lb [nLODEntry]byte
+	m.LODs = make([]LODRange, numLODs)
+	for i := range m.LODs {
+		if _, err := io.ReadFull(buf, lb[:]); err != nil {
+			return err
+		}
+		m.LODs[i] = LODRange{FaceIndex: int(binary.LittleEndian.Uint32(lb[:]))}
+	}
+
+	m.Bones = nil
+	m.Envelopes = nil
+	if hasSkinning(version) {
+		// Bones, followed by the name table their NameIndex fields point
+		// into.
+		bb := make([]byte, nBoneEntry)
+		nameIndices := make([]int, numBones)
+		m.Bones = make([]Bone, numBones)
+		for i := range m.Bones {
+			if _, err := io.ReadFull(buf, bb); err != nil {
+				return err
+			}
+			nameIndices[i] = int(binary.LittleEndian.Uint32(bb[0:4]))
+			m.Bones[i].ParentIndex = int16(binary.LittleEndian.Uint16(bb[6:8]))
+			for j := 0; j < 12; j++ {
+				m.Bones[i].Transform[j] = math.Float32frombits(binary.LittleEndian.Uint32(bb[8+j*4 : 12+j*4]))
+			}
+		}
+
+		names := make([]byte, nameTableBytes)
+		if _, err := io.ReadFull(buf, names); err != nil {
+			return err
+		}
+		for i, off := range nameIndices {
+			if off < 0 || off > len(names) {
+				return errors.New("bone name index out of range")
+			}
+			end := off
+			for end < len(names) && names[end] != 0 {
+				end++
+			}
+			m.Bones[i].Name = string(names[off:end])
+		}
+
+		// Envelopes
+		var eb [nEnvelope]byte
+		m.Envelopes = make([]VertexEnvelope, numVerts)
+		for i := range m.Envelopes {
+			if _, err := io.ReadFull(buf, eb[:]); err != nil {
+				return err
+			}
+			copy(m.Envelopes[i].Bones[:], eb[0:4])
+			copy(m.Envelopes[i].Weights[:], eb[4:8])
+		}
+	}
+
+	m.FaceSubsets = nil
+	if hasFaceSubsets(version) {
+		sb := make([]byte, nFaceSubset)
+		m.FaceSubsets = make([]FaceSubset, numSubsets)
+		for i := range m.FaceSubsets {
+			if _, err := io.ReadFull(buf, sb); err != nil {
+				return err
+			}
+			m.FaceSubsets[i] = FaceSubset{
+				FacesBegin: int(binary.LittleEndian.Uint32(sb[0:4])),
+				FacesCount: int(binary.LittleEndian.Uint32(sb[4:8])),
+				VertsBegin: int(binary.LittleEndian.Uint32(sb[8:12])),
+				VertsCount: int(binary.LittleEndian.Uint32(sb[12:16])),
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeV3Plus encodes the Version3_00-and-later mesh formats. The caller
+// must have already written the version signature line.
+func (m *Mesh) writeV3Plus(w io.Writer) error {
+	version := m.Version
+	var headerSize int
+	switch {
+	case hasFaceSubsets(version):
+		headerSize = nHeaderV5
+	case hasSkinning(version):
+		headerSize = nHeaderV4
+	default:
+		headerSize = nHeaderV3
+	}
+
+	h := make([]byte, headerSize)
+	binary.LittleEndian.PutUint16(h[0:2], uint16(headerSize))
+	if m.HasColor {
+		binary.LittleEndian.PutUint16(h[2:4], uint16(nColor))
+	} else {
+		binary.LittleEndian.PutUint16(h[2:4], uint16(nVertex))
+	}
+	binary.LittleEndian.PutUint16(h[4:6], uint16(nFace))
+	binary.LittleEndian.PutUint32(h[6:10], uint32(len(m.Vertices)))
+	binary.LittleEndian.PutUint32(h[10:14], uint32(len(m.Faces)))
+	binary.LittleEndian.PutUint32(h[14:18], uint32(len(m.LODs)))
+
+	var nameTable []byte
+	if hasSkinning(version) {
+		if len(m.Envelopes) != len(m.Vertices) {
+			return errors.New("rbxmesh: Envelopes must have one entry per Vertices entry")
+		}
+		nameTable = buildBoneNameTable(m.Bones)
+		binary.LittleEndian.PutUint16(h[18:20], uint16(len(m.Bones)))
+		binary.LittleEndian.PutUint32(h[20:24], uint32(len(nameTable)))
+	}
+	if hasFaceSubsets(version) {
+		binary.LittleEndian.PutUint16(h[24:26], uint16(len(m.FaceSubsets)))
+		binary.LittleEndian.PutUint16(h[26:28], uint16(m.NumHighQualityLODs))
+	}
+	if _, err := w.Write(h); err != nil {
+		return err
+	}
+
+	// Vertices
+	vb := make([]byte, nVertex)
+	if m.HasColor {
+		vb = make([]byte, nColor)
+	}
+	putvec := func(b []byte, v [3]float64) {
+		binary.LittleEndian.PutUint32(b[0:4], math.Float32bits(float32(v[0])))
+		binary.LittleEndian.PutUint32(b[4:8], math.Float32bits(float32(v[1])))
+		binary.LittleEndian.PutUint32(b[8:12], math.Float32bits(float32(v[2])))
+	}
+	for _, v := range m.Vertices {
+		putvec(vb[0:12], v.Position)
+		putvec(vb[12:24], v.Normal)
+		putvec(vb[24:36], v.Texture)
+		if m.HasColor {
+			copy(vb[36:40], v.Color[:])
+		}
+		if _, err := w.Write(vb); err != nil {
+			return err
+		}
+	}
+
+	// Faces
+	var fb [nFace]byte
+	for _, f := range m.Faces {
+		for _, index := range f {
+			if index < 0 || index >= len(m.Vertices) {
+				return errors.New("index out of range")
+			}
+		}
+		binary.LittleEndian.PutUint32(fb[0:4], uint32(f[0]))
+		binary.LittleEndian.PutUint32(fb[4:8], uint32(f[1]))
+		binary.LittleEndian.PutUint32(fb[8:12], uint32(f[2]))
+		if _, err := w.Write(fb[:]); err != nil {
+			return err
+		}
+	}
+
+	// LOD ranges
+	var lb [nLODEntry]byte
+	for _, lod := range m.LODs {
+		binary.LittleEndian.PutUint32(lb[:], uint32(lod.FaceIndex))
+		if _, err := w.Write(lb[:]); err != nil {
+			return err
+		}
+	}
+
+	if hasSkinning(version) {
+		nameIndices := boneNameIndices(m.Bones, nameTable)
+		bb := make([]byte, nBoneEntry)
+		for i, bone := range m.Bones {
+			binary.LittleEndian.PutUint32(bb[0:4], uint32(nameIndices[i]))
+			binary.LittleEndian.PutUint16(bb[6:8], uint16(bone.ParentIndex))
+			for j, f := range bone.Transform {
+				binary.LittleEndian.PutUint32(bb[8+j*4:12+j*4], math.Float32bits(f))
+			}
+			if _, err := w.Write(bb); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(nameTable); err != nil {
+			return err
+		}
+
+		var eb [nEnvelope]byte
+		for _, env := range m.Envelopes {
+			copy(eb[0:4], env.Bones[:])
+			copy(eb[4:8], env.Weights[:])
+			if _, err := w.Write(eb[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hasFaceSubsets(version) {
+		sb := make([]byte, nFaceSubset)
+		for _, s := range m.FaceSubsets {
+			binary.LittleEndian.PutUint32(sb[0:4], uint32(s.FacesBegin))
+			binary.LittleEndian.PutUint32(sb[4:8], uint32(s.FacesCount))
+			binary.LittleEndian.PutUint32(sb[8:12], uint32(s.VertsBegin))
+			binary.LittleEndian.PutUint32(sb[12:16], uint32(s.VertsCount))
+			if _, err := w.Write(sb); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildBoneNameTable concatenates each bone's name, NUL-terminated, into a
+// single blob suitable for writing after the bone array.
+func buildBoneNameTable(bones []Bone) []byte {
+	var table []byte
+	for _, b := range bones {
+		table = append(table, b.Name...)
+		table = append(table, 0)
+	}
+	return table
+}
+
+// boneNameIndices returns, for each bone, the byte offset of its name
+// within table, as produced by buildBoneNameTable.
+func boneNameIndices(bones []Bone, table []byte) []int {
+	indices := make([]int, len(bones))
+	offset := 0
+	for i, b := range bones {
+		indices[i] = offset
+		offset += len(b.Name) + 1
+	}
+	return indices
+}
@@ -0,0 +1,134 @@
+package rbxmesh
+
+import (
+	"bytes"
+	"testing"
+)
+
+func sampleMeshV3() *Mesh {
+	return &Mesh{
+		Version: Version3_00,
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{1, 0, 0}},
+			{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 1, 0}},
+		},
+		Faces: []MeshFace{{0, 1, 2}},
+		LODs:  []LODRange{{FaceIndex: 0}},
+	}
+}
+
+func roundTripV3Plus(t *testing.T, m *Mesh) *Mesh {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	got := &Mesh{}
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	return got
+}
+
+func TestMeshV3RoundTrip(t *testing.T) {
+	m := sampleMeshV3()
+	got := roundTripV3Plus(t, m)
+	if len(got.Vertices) != len(m.Vertices) || len(got.Faces) != len(m.Faces) || len(got.LODs) != len(m.LODs) {
+		t.Fatalf("expected %d/%d/%d vertices/faces/LODs, got %d/%d/%d",
+			len(m.Vertices), len(m.Faces), len(m.LODs), len(got.Vertices), len(got.Faces), len(got.LODs))
+	}
+	for i, v := range m.Vertices {
+		if v.Position != got.Vertices[i].Position {
+			t.Errorf("vertex %d: expected position %v, got %v", i, v.Position, got.Vertices[i].Position)
+		}
+	}
+}
+
+func TestMeshV4RoundTripSkinning(t *testing.T) {
+	m := sampleMeshV3()
+	m.Version = Version4_00
+	m.Bones = []Bone{
+		{Name: "Root", ParentIndex: -1},
+		{Name: "Child", ParentIndex: 0},
+	}
+	m.Envelopes = []VertexEnvelope{
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{1, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{1, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+	}
+
+	got := roundTripV3Plus(t, m)
+	if len(got.Bones) != len(m.Bones) {
+		t.Fatalf("expected %d bones, got %d", len(m.Bones), len(got.Bones))
+	}
+	for i, b := range m.Bones {
+		if got.Bones[i].Name != b.Name || got.Bones[i].ParentIndex != b.ParentIndex {
+			t.Errorf("bone %d: expected %+v, got %+v", i, b, got.Bones[i])
+		}
+	}
+	if len(got.Envelopes) != len(m.Envelopes) {
+		t.Fatalf("expected %d envelopes, got %d", len(m.Envelopes), len(got.Envelopes))
+	}
+	for i, e := range m.Envelopes {
+		if got.Envelopes[i] != e {
+			t.Errorf("envelope %d: expected %+v, got %+v", i, e, got.Envelopes[i])
+		}
+	}
+}
+
+func TestMeshV5RoundTripFaceSubsetsAndHQLODs(t *testing.T) {
+	m := sampleMeshV3()
+	m.Version = Version5_00
+	m.Bones = []Bone{{Name: "Root", ParentIndex: -1}}
+	m.Envelopes = []VertexEnvelope{
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+	}
+	m.FaceSubsets = []FaceSubset{{FacesBegin: 0, FacesCount: 1, VertsBegin: 0, VertsCount: 3}}
+	m.NumHighQualityLODs = 1
+
+	got := roundTripV3Plus(t, m)
+	if len(got.FaceSubsets) != 1 || got.FaceSubsets[0] != m.FaceSubsets[0] {
+		t.Errorf("expected FaceSubsets %v, got %v", m.FaceSubsets, got.FaceSubsets)
+	}
+	if got.NumHighQualityLODs != m.NumHighQualityLODs {
+		t.Errorf("expected NumHighQualityLODs %d, got %d", m.NumHighQualityLODs, got.NumHighQualityLODs)
+	}
+}
+
+func TestMeshReadFromResetsStaleFields(t *testing.T) {
+	var buf bytes.Buffer
+	v5 := sampleMeshV3()
+	v5.Version = Version5_00
+	v5.Bones = []Bone{{Name: "Root", ParentIndex: -1}}
+	v5.Envelopes = []VertexEnvelope{
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+		{Bones: [4]byte{0, 0, 0, 0}, Weights: [4]byte{255, 0, 0, 0}},
+	}
+	v5.FaceSubsets = []FaceSubset{{FacesBegin: 0, FacesCount: 1, VertsBegin: 0, VertsCount: 3}}
+	v5.NumHighQualityLODs = 1
+	if _, err := v5.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	m := &Mesh{}
+	if _, err := m.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+
+	buf.Reset()
+	v2 := &Mesh{Version: Version2_00, Vertices: sampleMeshV3().Vertices, Faces: sampleMeshV3().Faces}
+	if _, err := v2.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if _, err := m.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	if m.Bones != nil || m.Envelopes != nil || m.FaceSubsets != nil || m.LODs != nil || m.NumHighQualityLODs != 0 {
+		t.Errorf("expected V2 decode to clear stale V5 fields, got Bones=%v Envelopes=%v FaceSubsets=%v LODs=%v NumHighQualityLODs=%d",
+			m.Bones, m.Envelopes, m.FaceSubsets, m.LODs, m.NumHighQualityLODs)
+	}
+}
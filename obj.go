@@ -0,0 +1,250 @@
+package rbxmesh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadOBJ decodes a Wavefront OBJ file, filling in m.Vertices and m.Faces.
+// It understands v, vn, vt, and f lines; mtllib, usemtl, o, g, and s
+// directives are ignored without error. Faces may reference vertices in any
+// of the v, v/vt, v//vn, or v/vt/vn forms, and indices may be negative,
+// referring relative to the current end of the corresponding list, as
+// permitted by the OBJ format. As with the Version1_xx decoders, vertices
+// are deduplicated by their full (position, normal, texcoord) value into
+// m.Vertices, and vt's V is flipped from OBJ convention back to the mesh
+// format's. If a v line carries the non-standard "v x y z r g b" color
+// extension, m.HasColor is set and the color is attached to every vertex
+// that references that position.
+//
+// ReadOBJ does not modify m.Version.
+func (m *Mesh) ReadOBJ(r io.Reader) error {
+	var positions [][3]float64
+	var colors [][4]byte
+	var normals [][3]float64
+	var texcoords [][3]float64
+
+	verts := map[MeshVertex]int{}
+	m.Vertices = nil
+	m.Faces = nil
+	m.HasColor = false
+
+	resolve := func(n, index int) (int, error) {
+		if index < 0 {
+			index = n + index + 1
+		}
+		if index < 1 || index > n {
+			return 0, fmt.Errorf("index %d out of range (%d available)", index, n)
+		}
+		return index - 1, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "v":
+			var p [3]float64
+			var c [4]byte
+			hasColor := false
+			switch len(fields) - 1 {
+			case 3:
+				for i := 0; i < 3; i++ {
+					f, err := strconv.ParseFloat(fields[1+i], 64)
+					if err != nil {
+						return err
+					}
+					p[i] = f
+				}
+			case 6:
+				for i := 0; i < 3; i++ {
+					f, err := strconv.ParseFloat(fields[1+i], 64)
+					if err != nil {
+						return err
+					}
+					p[i] = f
+				}
+				for i := 0; i < 3; i++ {
+					f, err := strconv.ParseFloat(fields[4+i], 64)
+					if err != nil {
+						return err
+					}
+					c[i] = byte(f*255 + 0.5)
+				}
+				c[3] = 0xFF
+				hasColor = true
+			default:
+				return fmt.Errorf("unexpected number of fields in v line: %q", line)
+			}
+			positions = append(positions, p)
+			colors = append(colors, c)
+			if hasColor {
+				m.HasColor = true
+			}
+
+		case "vn":
+			if len(fields) != 4 {
+				return fmt.Errorf("unexpected number of fields in vn line: %q", line)
+			}
+			var n [3]float64
+			for i := 0; i < 3; i++ {
+				f, err := strconv.ParseFloat(fields[1+i], 64)
+				if err != nil {
+					return err
+				}
+				n[i] = f
+			}
+			normals = append(normals, n)
+
+		case "vt":
+			if len(fields) < 2 {
+				return fmt.Errorf("unexpected number of fields in vt line: %q", line)
+			}
+			var t [3]float64
+			for i := 0; i < len(fields)-1 && i < 3; i++ {
+				f, err := strconv.ParseFloat(fields[1+i], 64)
+				if err != nil {
+					return err
+				}
+				t[i] = f
+			}
+			t[1] = 1 - t[1]
+			texcoords = append(texcoords, t)
+
+		case "f":
+			if len(fields) < 4 {
+				return fmt.Errorf("face has fewer than 3 vertices: %q", line)
+			}
+			indices := make([]int, len(fields)-1)
+			for i, field := range fields[1:] {
+				parts := strings.Split(field, "/")
+				v := MeshVertex{}
+
+				pi, err := strconv.Atoi(parts[0])
+				if err != nil {
+					return err
+				}
+				pi, err = resolve(len(positions), pi)
+				if err != nil {
+					return err
+				}
+				v.Position = positions[pi]
+				v.Color = colors[pi]
+
+				if len(parts) >= 2 && parts[1] != "" {
+					ti, err := strconv.Atoi(parts[1])
+					if err != nil {
+						return err
+					}
+					ti, err = resolve(len(texcoords), ti)
+					if err != nil {
+						return err
+					}
+					v.Texture = texcoords[ti]
+				}
+
+				if len(parts) >= 3 && parts[2] != "" {
+					ni, err := strconv.Atoi(parts[2])
+					if err != nil {
+						return err
+					}
+					ni, err = resolve(len(normals), ni)
+					if err != nil {
+						return err
+					}
+					v.Normal = normals[ni]
+				}
+
+				index, ok := verts[v]
+				if !ok {
+					index = len(verts)
+					verts[v] = index
+				}
+				indices[i] = index
+			}
+			// Fan-triangulate faces with more than 3 vertices, as done by
+			// most OBJ consumers.
+			for i := 1; i+1 < len(indices); i++ {
+				m.Faces = append(m.Faces, MeshFace{indices[0], indices[i], indices[i+1]})
+			}
+
+		case "mtllib", "usemtl", "o", "g", "s":
+			// Ignored.
+
+		default:
+			// Ignore unrecognized directives.
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	m.Vertices = make([]MeshVertex, len(verts))
+	for vert, index := range verts {
+		if !m.HasColor {
+			vert.Color = [4]byte{}
+		}
+		m.Vertices[index] = vert
+	}
+	return nil
+}
+
+// WriteOBJ encodes the mesh as a Wavefront OBJ file and writes it to w. It
+// emits v, vn, and vt lines from m.Vertices (flipping V back to OBJ
+// convention) followed by one f line per MeshFace, using 1-based indices.
+// If m.HasColor is set, each v line additionally carries the non-standard
+// "r g b" color extension, with components normalized to the 0-1 range.
+func (m *Mesh) WriteOBJ(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, v := range m.Vertices {
+		if m.HasColor {
+			if _, err := fmt.Fprintf(bw, "v %g %g %g %g %g %g\n",
+				v.Position[0], v.Position[1], v.Position[2],
+				float64(v.Color[0])/255, float64(v.Color[1])/255, float64(v.Color[2])/255,
+			); err != nil {
+				return err
+			}
+		} else {
+			if _, err := fmt.Fprintf(bw, "v %g %g %g\n", v.Position[0], v.Position[1], v.Position[2]); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range m.Vertices {
+		if _, err := fmt.Fprintf(bw, "vn %g %g %g\n", v.Normal[0], v.Normal[1], v.Normal[2]); err != nil {
+			return err
+		}
+	}
+	for _, v := range m.Vertices {
+		if _, err := fmt.Fprintf(bw, "vt %g %g\n", v.Texture[0], 1-v.Texture[1]); err != nil {
+			return err
+		}
+	}
+
+	for _, face := range m.Faces {
+		for _, index := range face {
+			if index < 0 || index >= len(m.Vertices) {
+				return errors.New("index out of range")
+			}
+		}
+		if _, err := fmt.Fprintf(bw, "f %d/%d/%d %d/%d/%d %d/%d/%d\n",
+			face[0]+1, face[0]+1, face[0]+1,
+			face[1]+1, face[1]+1, face[1]+1,
+			face[2]+1, face[2]+1, face[2]+1,
+		); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,98 @@
+package rbxmesh
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOBJReadOBJRoundTrip(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{1, 0, 0}},
+			{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}, Texture: [3]float64{0, 1, 0}},
+		},
+		Faces: []MeshFace{{0, 1, 2}},
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteOBJ(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got Mesh
+	if err := got.ReadOBJ(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Vertices) != len(m.Vertices) {
+		t.Fatalf("expected %d vertices, got %d", len(m.Vertices), len(got.Vertices))
+	}
+	if len(got.Faces) != len(m.Faces) {
+		t.Fatalf("expected %d faces, got %d", len(m.Faces), len(got.Faces))
+	}
+	for i, v := range m.Vertices {
+		if v.Position != got.Vertices[i].Position {
+			t.Errorf("vertex %d: expected position %v, got %v", i, v.Position, got.Vertices[i].Position)
+		}
+		if v.Normal != got.Vertices[i].Normal {
+			t.Errorf("vertex %d: expected normal %v, got %v", i, v.Normal, got.Vertices[i].Normal)
+		}
+		if v.Texture != got.Vertices[i].Texture {
+			t.Errorf("vertex %d: expected texture %v, got %v", i, v.Texture, got.Vertices[i].Texture)
+		}
+	}
+}
+
+func TestReadOBJNegativeIndices(t *testing.T) {
+	src := strings.Join([]string{
+		"v 0 0 0",
+		"v 1 0 0",
+		"v 0 1 0",
+		"f -3 -2 -1",
+	}, "\n")
+
+	var m Mesh
+	if err := m.ReadOBJ(strings.NewReader(src)); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Vertices) != 3 {
+		t.Fatalf("expected 3 vertices, got %d", len(m.Vertices))
+	}
+	if len(m.Faces) != 1 {
+		t.Fatalf("expected 1 face, got %d", len(m.Faces))
+	}
+}
+
+func TestReadOBJFanTriangulation(t *testing.T) {
+	src := strings.Join([]string{
+		"v 0 0 0",
+		"v 1 0 0",
+		"v 1 1 0",
+		"v 0 1 0",
+		"f 1 2 3 4",
+	}, "\n")
+
+	var m Mesh
+	if err := m.ReadOBJ(strings.NewReader(src)); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Faces) != 2 {
+		t.Fatalf("expected a quad to fan-triangulate into 2 faces, got %d", len(m.Faces))
+	}
+}
+
+func TestReadOBJColorExtension(t *testing.T) {
+	src := "v 0 0 0 1 0 0\nv 1 0 0 1 0 0\nv 0 1 0 1 0 0\nf 1 2 3\n"
+
+	var m Mesh
+	if err := m.ReadOBJ(strings.NewReader(src)); err != nil {
+		t.Fatal(err)
+	}
+	if !m.HasColor {
+		t.Fatal("expected HasColor to be set from the v color extension")
+	}
+	if m.Vertices[0].Color != [4]byte{255, 0, 0, 255} {
+		t.Errorf("expected red vertex color, got %v", m.Vertices[0].Color)
+	}
+}
@@ -0,0 +1,341 @@
+package rbxmesh
+
+import (
+	"fmt"
+	"math"
+)
+
+// MeshErrorKind categorizes the kind of problem reported by a MeshError.
+type MeshErrorKind string
+
+const (
+	ErrFaceIndexOutOfRange MeshErrorKind = "face index out of range"
+	ErrDegenerateFace      MeshErrorKind = "degenerate face"
+	ErrDuplicateVertex     MeshErrorKind = "duplicate vertex"
+	ErrInvalidNormal       MeshErrorKind = "invalid normal"
+	ErrNonFiniteValue      MeshErrorKind = "non-finite value"
+	ErrUnreferencedVertex  MeshErrorKind = "unreferenced vertex"
+)
+
+// MeshError describes a single problem found by Mesh.Validate. Face and
+// Vertex are indices into Mesh.Faces and Mesh.Vertices respectively, or -1
+// if not applicable to the error's Kind. For ErrFaceIndexOutOfRange, Vertex
+// holds the offending index as stored in the face, which may be negative.
+type MeshError struct {
+	Kind   MeshErrorKind
+	Face   int
+	Vertex int
+}
+
+func (e MeshError) Error() string {
+	switch {
+	case e.Face >= 0 && e.Vertex >= 0:
+		return fmt.Sprintf("%s: face %d, vertex %d", e.Kind, e.Face, e.Vertex)
+	case e.Face >= 0:
+		return fmt.Sprintf("%s: face %d", e.Kind, e.Face)
+	case e.Vertex >= 0:
+		return fmt.Sprintf("%s: vertex %d", e.Kind, e.Vertex)
+	default:
+		return string(e.Kind)
+	}
+}
+
+// Validate checks the mesh for common problems: face indices out of range,
+// degenerate triangles, duplicate vertices, non-unit or zero-length
+// normals, NaN or Inf floats in positions, normals, or texcoords, and
+// vertices not referenced by any face. It reports every problem found
+// rather than stopping at the first.
+func (m *Mesh) Validate() []MeshError {
+	var errs []MeshError
+
+	referenced := make([]bool, len(m.Vertices))
+	for fi, f := range m.Faces {
+		inRange := true
+		for _, idx := range f {
+			if idx < 0 || idx >= len(m.Vertices) {
+				errs = append(errs, MeshError{Kind: ErrFaceIndexOutOfRange, Face: fi, Vertex: idx})
+				inRange = false
+				continue
+			}
+			referenced[idx] = true
+		}
+		if !inRange {
+			continue
+		}
+		if f[0] == f[1] || f[1] == f[2] || f[0] == f[2] {
+			errs = append(errs, MeshError{Kind: ErrDegenerateFace, Face: fi, Vertex: -1})
+		} else if triangleArea(m.Vertices[f[0]].Position, m.Vertices[f[1]].Position, m.Vertices[f[2]].Position) == 0 {
+			errs = append(errs, MeshError{Kind: ErrDegenerateFace, Face: fi, Vertex: -1})
+		}
+	}
+
+	seen := make(map[MeshVertex]int, len(m.Vertices))
+	for vi, v := range m.Vertices {
+		if !finite3(v.Position) || !finite3(v.Normal) || !finite3(v.Texture) {
+			errs = append(errs, MeshError{Kind: ErrNonFiniteValue, Face: -1, Vertex: vi})
+		}
+		if n := length3(v.Normal); n == 0 || math.Abs(n-1) > 1e-3 {
+			errs = append(errs, MeshError{Kind: ErrInvalidNormal, Face: -1, Vertex: vi})
+		}
+		if prior, ok := seen[v]; ok {
+			errs = append(errs, MeshError{Kind: ErrDuplicateVertex, Face: -1, Vertex: prior})
+			errs = append(errs, MeshError{Kind: ErrDuplicateVertex, Face: -1, Vertex: vi})
+		} else {
+			seen[v] = vi
+		}
+		if !referenced[vi] {
+			errs = append(errs, MeshError{Kind: ErrUnreferencedVertex, Face: -1, Vertex: vi})
+		}
+	}
+
+	return errs
+}
+
+func finite3(v [3]float64) bool {
+	for _, f := range v {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func length3(v [3]float64) float64 {
+	return math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+}
+
+func sub3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// triangleArea returns twice the (unsigned) area of the triangle, computed as
+// the length of the cross product of two of its edges, matching the area weighting used by
+// Repair's normal recomputation.
+func triangleArea(a, b, c [3]float64) float64 {
+	return length3(cross3(sub3(b, a), sub3(c, a)))
+}
+
+// RepairOptions selects which repairs Mesh.Repair performs. All fields
+// default to off.
+type RepairOptions struct {
+	// RecomputeNormals replaces every vertex normal with an area-weighted
+	// average of the normals of the faces that reference it.
+	RecomputeNormals bool
+	// WeldEpsilon, if greater than zero, merges vertices whose positions
+	// are within this Euclidean distance of each other.
+	WeldEpsilon float64
+	// DropDegenerateFaces removes faces with out-of-range, repeated, or
+	// collinear vertex indices.
+	DropDegenerateFaces bool
+	// DropUnreferencedVertices removes vertices not referenced by any
+	// face, remapping face indices accordingly. It runs after the other
+	// options, so that vertices orphaned by welding or degenerate-face
+	// removal are also dropped.
+	DropUnreferencedVertices bool
+	// NormalizeUVs wraps each vertex's Tu and Tv texture coordinates into
+	// the [0, 1) range.
+	NormalizeUVs bool
+}
+
+// Repair applies the repairs selected by opts to the mesh.
+//
+// Repair does not update LODs or FaceSubsets, which index into Faces and
+// Vertices by position: if DropDegenerateFaces, WeldEpsilon, or
+// DropUnreferencedVertices removes entries, callers relying on those fields
+// should recompute them afterward. Bones are left unchanged. Envelopes, which
+// correspond one-to-one with Vertices, are kept in sync.
+func (m *Mesh) Repair(opts RepairOptions) error {
+	if opts.DropDegenerateFaces {
+		m.dropDegenerateFaces()
+	}
+	if opts.WeldEpsilon > 0 {
+		m.weldVertices(opts.WeldEpsilon)
+	}
+	if opts.RecomputeNormals {
+		m.recomputeNormals()
+	}
+	if opts.NormalizeUVs {
+		m.normalizeUVs()
+	}
+	if opts.DropUnreferencedVertices {
+		m.dropUnreferencedVertices()
+	}
+	return nil
+}
+
+func (m *Mesh) dropDegenerateFaces() {
+	faces := m.Faces[:0]
+	for _, f := range m.Faces {
+		ok := true
+		for _, idx := range f {
+			if idx < 0 || idx >= len(m.Vertices) {
+				ok = false
+			}
+		}
+		if ok && (f[0] == f[1] || f[1] == f[2] || f[0] == f[2]) {
+			ok = false
+		}
+		if ok && triangleArea(m.Vertices[f[0]].Position, m.Vertices[f[1]].Position, m.Vertices[f[2]].Position) == 0 {
+			ok = false
+		}
+		if ok {
+			faces = append(faces, f)
+		}
+	}
+	m.Faces = faces
+}
+
+// weldVertices merges vertices whose positions are within epsilon of each
+// other, keeping the first vertex encountered in each cluster and remapping
+// face indices to match. Vertices are bucketed into a grid of epsilon-sized
+// cells so that only nearby vertices are compared.
+func (m *Mesh) weldVertices(epsilon float64) {
+	type cell struct{ x, y, z int64 }
+	cellOf := func(p [3]float64) cell {
+		return cell{
+			int64(math.Floor(p[0] / epsilon)),
+			int64(math.Floor(p[1] / epsilon)),
+			int64(math.Floor(p[2] / epsilon)),
+		}
+	}
+
+	// buckets holds, per cell, the indices of kept vertices (i.e. indices
+	// into kept, not into m.Vertices) so that lookups stay valid as kept is
+	// built up alongside m.Vertices rather than in place over it.
+	buckets := make(map[cell][]int)
+	remap := make([]int, len(m.Vertices))
+	kept := make([]MeshVertex, 0, len(m.Vertices))
+	keptEnvelopes := make([]VertexEnvelope, 0, len(m.Envelopes))
+
+	for vi, v := range m.Vertices {
+		c := cellOf(v.Position)
+		merged := -1
+	search:
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				for dz := int64(-1); dz <= 1; dz++ {
+					neighbor := cell{c.x + dx, c.y + dy, c.z + dz}
+					for _, ki := range buckets[neighbor] {
+						if length3(sub3(v.Position, kept[ki].Position)) <= epsilon {
+							merged = ki
+							break search
+						}
+					}
+				}
+			}
+		}
+		if merged >= 0 {
+			remap[vi] = merged
+			continue
+		}
+		newIndex := len(kept)
+		kept = append(kept, v)
+		if len(m.Envelopes) == len(m.Vertices) {
+			keptEnvelopes = append(keptEnvelopes, m.Envelopes[vi])
+		}
+		remap[vi] = newIndex
+		buckets[c] = append(buckets[c], newIndex)
+	}
+
+	m.Vertices = kept
+	if len(m.Envelopes) > 0 {
+		m.Envelopes = keptEnvelopes
+	}
+	for fi, f := range m.Faces {
+		for i, idx := range f {
+			if idx >= 0 && idx < len(remap) {
+				m.Faces[fi][i] = remap[idx]
+			}
+		}
+	}
+}
+
+// recomputeNormals replaces every vertex normal with the area-weighted
+// average of the (unnormalized) normals of the faces that reference it.
+func (m *Mesh) recomputeNormals() {
+	sums := make([][3]float64, len(m.Vertices))
+	for _, f := range m.Faces {
+		ok := true
+		for _, idx := range f {
+			if idx < 0 || idx >= len(m.Vertices) {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+		n := cross3(sub3(m.Vertices[f[1]].Position, m.Vertices[f[0]].Position), sub3(m.Vertices[f[2]].Position, m.Vertices[f[0]].Position))
+		for _, idx := range f {
+			sums[idx][0] += n[0]
+			sums[idx][1] += n[1]
+			sums[idx][2] += n[2]
+		}
+	}
+	for vi := range m.Vertices {
+		if l := length3(sums[vi]); l > 0 {
+			m.Vertices[vi].Normal = [3]float64{sums[vi][0] / l, sums[vi][1] / l, sums[vi][2] / l}
+		}
+	}
+}
+
+// normalizeUVs wraps each vertex's Tu and Tv into the [0, 1) range.
+func (m *Mesh) normalizeUVs() {
+	wrap := func(f float64) float64 {
+		f = math.Mod(f, 1)
+		if f < 0 {
+			f++
+		}
+		return f
+	}
+	for vi := range m.Vertices {
+		m.Vertices[vi].Texture[0] = wrap(m.Vertices[vi].Texture[0])
+		m.Vertices[vi].Texture[1] = wrap(m.Vertices[vi].Texture[1])
+	}
+}
+
+// dropUnreferencedVertices removes vertices not referenced by any face,
+// remapping face indices to match.
+func (m *Mesh) dropUnreferencedVertices() {
+	referenced := make([]bool, len(m.Vertices))
+	for _, f := range m.Faces {
+		for _, idx := range f {
+			if idx >= 0 && idx < len(referenced) {
+				referenced[idx] = true
+			}
+		}
+	}
+
+	remap := make([]int, len(m.Vertices))
+	kept := m.Vertices[:0]
+	keptEnvelopes := m.Envelopes[:0]
+	for vi, v := range m.Vertices {
+		if !referenced[vi] {
+			continue
+		}
+		remap[vi] = len(kept)
+		kept = append(kept, v)
+		if len(m.Envelopes) == len(m.Vertices) {
+			keptEnvelopes = append(keptEnvelopes, m.Envelopes[vi])
+		}
+	}
+	m.Vertices = kept
+	if len(m.Envelopes) > 0 {
+		m.Envelopes = keptEnvelopes
+	}
+
+	for fi, f := range m.Faces {
+		for i, idx := range f {
+			if idx >= 0 && idx < len(remap) {
+				m.Faces[fi][i] = remap[idx]
+			}
+		}
+	}
+}
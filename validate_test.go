@@ -0,0 +1,135 @@
+package rbxmesh
+
+import "testing"
+
+func TestValidateDetectsProblems(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}},
+			{Position: [3]float64{1, 0, 0}}, // duplicate of vertex 1
+			{Position: [3]float64{5, 5, 5}}, // unreferenced
+		},
+		Faces: []MeshFace{{0, 1, 1}}, // degenerate: repeated index
+	}
+
+	errs := m.Validate()
+	has := func(kind MeshErrorKind) bool {
+		for _, e := range errs {
+			if e.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+	if !has(ErrDegenerateFace) {
+		t.Error("expected ErrDegenerateFace")
+	}
+	if !has(ErrDuplicateVertex) {
+		t.Error("expected ErrDuplicateVertex")
+	}
+	if !has(ErrUnreferencedVertex) {
+		t.Error("expected ErrUnreferencedVertex")
+	}
+	if !has(ErrInvalidNormal) {
+		t.Error("expected ErrInvalidNormal for zero-length normals")
+	}
+}
+
+func TestValidateZeroLengthNormal(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}, Normal: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}, Normal: [3]float64{0, 1, 0}},
+			{Position: [3]float64{0, 1, 0}, Normal: [3]float64{0, 1, 0}},
+		},
+		Faces: []MeshFace{{0, 1, 2}},
+	}
+	errs := m.Validate()
+	found := false
+	for _, e := range errs {
+		if e.Kind == ErrInvalidNormal && e.Vertex == 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected ErrInvalidNormal for vertex 0's zero-length normal")
+	}
+}
+
+func TestRepairDropDegenerateAndUnreferenced(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}},
+			{Position: [3]float64{0, 1, 0}},
+			{Position: [3]float64{9, 9, 9}}, // unreferenced
+		},
+		Faces: []MeshFace{{0, 1, 2}, {0, 1, 1}}, // second face is degenerate
+	}
+	if err := m.Repair(RepairOptions{DropDegenerateFaces: true, DropUnreferencedVertices: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Faces) != 1 {
+		t.Errorf("expected 1 face after dropping the degenerate one, got %d", len(m.Faces))
+	}
+	if len(m.Vertices) != 3 {
+		t.Errorf("expected 3 vertices after dropping the unreferenced one, got %d", len(m.Vertices))
+	}
+}
+
+func TestRepairWeldVertices(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}},
+			{Position: [3]float64{0, 0, 0}}, // duplicate of vertex 0
+			{Position: [3]float64{10, 0, 0}},
+			{Position: [3]float64{20, 0, 0}},
+			{Position: [3]float64{10.01, 0, 0}}, // should weld into vertex 2
+		},
+		Faces: []MeshFace{{0, 1, 2}, {2, 3, 4}},
+	}
+	if err := m.Repair(RepairOptions{WeldEpsilon: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Vertices) != 3 {
+		t.Fatalf("expected welding to leave 3 distinct vertices, got %d: %+v", len(m.Vertices), m.Vertices)
+	}
+	if m.Faces[1][0] != m.Faces[1][2] {
+		t.Errorf("expected vertex 10.01 to weld into the same vertex as 10, got face %v", m.Faces[1])
+	}
+}
+
+func TestRepairRecomputeNormals(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Position: [3]float64{0, 0, 0}},
+			{Position: [3]float64{1, 0, 0}},
+			{Position: [3]float64{0, 1, 0}},
+		},
+		Faces: []MeshFace{{0, 1, 2}},
+	}
+	if err := m.Repair(RepairOptions{RecomputeNormals: true}); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range m.Vertices {
+		if n := length3(v.Normal); n < 0.99 || n > 1.01 {
+			t.Errorf("vertex %d: expected unit normal, got length %g", i, n)
+		}
+	}
+}
+
+func TestRepairNormalizeUVs(t *testing.T) {
+	m := &Mesh{
+		Vertices: []MeshVertex{
+			{Texture: [3]float64{1.5, -0.25, 0}},
+		},
+	}
+	if err := m.Repair(RepairOptions{NormalizeUVs: true}); err != nil {
+		t.Fatal(err)
+	}
+	got := m.Vertices[0].Texture
+	if got[0] < 0 || got[0] >= 1 || got[1] < 0 || got[1] >= 1 {
+		t.Errorf("expected UVs wrapped into [0, 1), got %v", got)
+	}
+}